@@ -0,0 +1,162 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package canonicaljson implements the OLPC canonical JSON form
+// (http://wiki.laptop.org/go/Canonical_JSON): object keys sorted by byte
+// order, no insignificant whitespace, a restricted string escape set, and
+// integer-only numbers. Two semantically equal values always marshal to the
+// same bytes, which contract-go's signing pipeline relies on so a contract
+// map with non-deterministic Go map iteration order still produces a
+// byte-identical payload to sign and fingerprint.
+package canonicaljson
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"unicode/utf8"
+)
+
+// Marshal encodes v as canonical JSON. Unlike encoding/json, it rejects
+// floating point numbers with a fractional part, NaN and Inf (canonical JSON
+// has no notion of a decimal point) and non-UTF-8 strings; integral floats,
+// such as those json.Unmarshal produces, are written as integers.
+func Marshal(v any) ([]byte, error) {
+	buf := make([]byte, 0, 256)
+	buf, err := appendValue(buf, v)
+	if err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
+func appendValue(buf []byte, v any) ([]byte, error) {
+	switch val := v.(type) {
+	case nil:
+		return append(buf, "null"...), nil
+	case bool:
+		if val {
+			return append(buf, "true"...), nil
+		}
+		return append(buf, "false"...), nil
+	case string:
+		return appendString(buf, val)
+	case map[string]any:
+		return appendObject(buf, val)
+	case []any:
+		return appendArray(buf, val)
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return append(buf, fmt.Sprintf("%d", val)...), nil
+	case float64:
+		return appendFloat64(buf, val)
+	case float32:
+		return appendFloat64(buf, float64(val))
+	default:
+		return nil, fmt.Errorf("canonicaljson: unsupported type %T", v)
+	}
+}
+
+// appendFloat64 only accepts values with no fractional part, since canonical
+// JSON integers never carry a decimal point and JSON itself has no way to
+// distinguish NaN/Inf.
+func appendFloat64(buf []byte, f float64) ([]byte, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return nil, fmt.Errorf("canonicaljson: NaN/Inf are not representable")
+	}
+	if f != math.Trunc(f) {
+		return nil, fmt.Errorf("canonicaljson: non-integer numbers are not allowed, got %v", f)
+	}
+	return strconv.AppendInt(buf, int64(f), 10), nil
+}
+
+func appendObject(buf []byte, obj map[string]any) ([]byte, error) {
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		if !utf8.ValidString(k) {
+			return nil, fmt.Errorf("canonicaljson: object key is not valid UTF-8")
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	buf = append(buf, '{')
+	for i, k := range keys {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		var err error
+		buf, err = appendString(buf, k)
+		if err != nil {
+			return nil, err
+		}
+		buf = append(buf, ':')
+		buf, err = appendValue(buf, obj[k])
+		if err != nil {
+			return nil, err
+		}
+	}
+	return append(buf, '}'), nil
+}
+
+func appendArray(buf []byte, arr []any) ([]byte, error) {
+	buf = append(buf, '[')
+	for i, elem := range arr {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		var err error
+		buf, err = appendValue(buf, elem)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return append(buf, ']'), nil
+}
+
+// allowedEscapes is the exhaustive set of escapes canonical JSON permits;
+// notably "\/" is not among them, unlike encoding/json's default encoder.
+var allowedEscapes = map[byte]byte{
+	'"':  '"',
+	'\\': '\\',
+	'\b': 'b',
+	'\f': 'f',
+	'\n': 'n',
+	'\r': 'r',
+	'\t': 't',
+}
+
+func appendString(buf []byte, s string) ([]byte, error) {
+	if !utf8.ValidString(s) {
+		return nil, fmt.Errorf("canonicaljson: string is not valid UTF-8")
+	}
+	buf = append(buf, '"')
+	for _, r := range s {
+		if r < utf8.RuneSelf {
+			b := byte(r)
+			if esc, ok := allowedEscapes[b]; ok {
+				buf = append(buf, '\\', esc)
+				continue
+			}
+			if b < 0x20 {
+				buf = append(buf, fmt.Sprintf(`\u%04x`, b)...)
+				continue
+			}
+			buf = append(buf, b)
+			continue
+		}
+		buf = utf8.AppendRune(buf, r)
+	}
+	return append(buf, '"'), nil
+}
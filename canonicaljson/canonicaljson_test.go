@@ -0,0 +1,86 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package canonicaljson
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarshalSortsKeys(t *testing.T) {
+	data, err := Marshal(map[string]any{"b": 1, "a": 2, "c": 3})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"a":2,"b":1,"c":3}`, string(data))
+}
+
+func TestMarshalMapOrderIndependence(t *testing.T) {
+	a, err := Marshal(map[string]any{"workload": "x", "env": "y", "auth": "z"})
+	assert.NoError(t, err)
+	b, err := Marshal(map[string]any{"auth": "z", "env": "y", "workload": "x"})
+	assert.NoError(t, err)
+	assert.Equal(t, a, b)
+}
+
+func TestMarshalRejectsFloat(t *testing.T) {
+	_, err := Marshal(map[string]any{"a": 1.5})
+	assert.Error(t, err)
+}
+
+func TestMarshalStringEscapes(t *testing.T) {
+	data, err := Marshal("a\"b\\c\nd/e")
+	assert.NoError(t, err)
+	// "/" must never be escaped in canonical JSON, unlike encoding/json.
+	assert.Equal(t, `"a\"b\\c\nd/e"`, string(data))
+}
+
+func TestMarshalIdempotent(t *testing.T) {
+	original := map[string]any{"b": []any{1, 2, 3}, "a": map[string]any{"nested": true}}
+
+	first, err := Marshal(original)
+	assert.NoError(t, err)
+
+	var roundTripped any
+	assert.NoError(t, json.Unmarshal(first, &roundTripped))
+
+	second, err := Marshal(roundTripped)
+	assert.NoError(t, err)
+
+	assert.Equal(t, first, second)
+}
+
+func FuzzMarshalRoundTrip(f *testing.F) {
+	f.Add(`{"b":1,"a":[true,false,null,"x"]}`)
+
+	f.Fuzz(func(t *testing.T, input string) {
+		var v any
+		if json.Unmarshal([]byte(input), &v) != nil {
+			t.Skip("not valid JSON, canonical encoder isn't meant to handle it")
+		}
+
+		first, err := Marshal(v)
+		if err != nil {
+			t.Skip("value uses a feature canonical JSON rejects, e.g. floats")
+		}
+
+		var decoded any
+		assert.NoError(t, json.Unmarshal(first, &decoded))
+
+		second, err := Marshal(decoded)
+		assert.NoError(t, err)
+		assert.Equal(t, first, second)
+	})
+}
@@ -0,0 +1,99 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package pkcs11 lets contract-go sign and decrypt contracts with a key
+// that never leaves an HSM, by delegating to a PKCS#11 module.
+package pkcs11
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Environment variables consulted when a Config is not supplied explicitly,
+// mirroring the HPCR_* convention used by encrypt/common.
+const (
+	KeyEnvModule   = "HPCR_PKCS11_MODULE"
+	KeyEnvPin      = "HPCR_PKCS11_PIN"
+	KeyEnvKeyLabel = "HPCR_PKCS11_KEY_LABEL"
+)
+
+// Mechanism identifies the PKCS#11 signing/decryption mechanism to use with
+// the configured key.
+type Mechanism string
+
+const (
+	MechanismRSAPKCS Mechanism = "RSA_PKCS"
+	MechanismRSAOAEP Mechanism = "RSA_PKCS_OAEP"
+	MechanismRSAPSS  Mechanism = "RSA_PKCS_PSS"
+	MechanismECDSA   Mechanism = "ECDSA"
+)
+
+// Config describes how to reach a PKCS#11 module and which key/mechanism to
+// use on it. It can be loaded from YAML/JSON or populated from environment
+// variables via ConfigFromEnv.
+type Config struct {
+	Module     string    `json:"module" yaml:"module"`
+	Slot       *uint     `json:"slot,omitempty" yaml:"slot,omitempty"`
+	TokenLabel string    `json:"token-label,omitempty" yaml:"token-label,omitempty"`
+	Pin        string    `json:"pin" yaml:"pin"`
+	KeyLabel   string    `json:"key-label,omitempty" yaml:"key-label,omitempty"`
+	KeyID      string    `json:"key-id,omitempty" yaml:"key-id,omitempty"`
+	Mechanism  Mechanism `json:"mechanism" yaml:"mechanism"`
+}
+
+// Validate checks that Config carries enough information to open a session
+// and locate a single key.
+func (c Config) Validate() error {
+	if c.Module == "" {
+		return fmt.Errorf("pkcs11: module path is required")
+	}
+	if c.Slot == nil && c.TokenLabel == "" {
+		return fmt.Errorf("pkcs11: either slot or token-label is required")
+	}
+	if c.KeyLabel == "" && c.KeyID == "" {
+		return fmt.Errorf("pkcs11: either key-label or key-id is required")
+	}
+	if c.Mechanism == "" {
+		return fmt.Errorf("pkcs11: mechanism is required")
+	}
+	return nil
+}
+
+// ConfigFromJSON parses a Config out of a JSON document; YAML syntax that is
+// not also valid JSON is rejected.
+func ConfigFromJSON(data []byte) (Config, error) {
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+// ConfigFromEnv builds a Config from the HPCR_PKCS11_* environment
+// variables, returning ok=false when HPCR_PKCS11_MODULE is unset so callers
+// can fall back to OpenSSL/crypto signing.
+func ConfigFromEnv() (cfg Config, ok bool) {
+	module, ok := os.LookupEnv(KeyEnvModule)
+	if !ok {
+		return Config{}, false
+	}
+	return Config{
+		Module:    module,
+		Pin:       os.Getenv(KeyEnvPin),
+		KeyLabel:  os.Getenv(KeyEnvKeyLabel),
+		Mechanism: MechanismRSAOAEP,
+	}, true
+}
@@ -0,0 +1,59 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pkcs11
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigValidate(t *testing.T) {
+	assert.Error(t, Config{}.Validate())
+
+	assert.Error(t, Config{Module: "/usr/lib/softhsm/libsofthsm2.so"}.Validate())
+
+	assert.NoError(t, Config{
+		Module:     "/usr/lib/softhsm/libsofthsm2.so",
+		TokenLabel: "contract-go",
+		KeyLabel:   "hpcr-signing-key",
+		Mechanism:  MechanismRSAOAEP,
+	}.Validate())
+}
+
+func TestConfigFromJSON(t *testing.T) {
+	cfg, err := ConfigFromJSON([]byte(`{
+		"module": "/usr/lib/softhsm/libsofthsm2.so",
+		"token-label": "contract-go",
+		"pin": "1234",
+		"key-label": "hpcr-signing-key",
+		"mechanism": "RSA_PKCS_OAEP"
+	}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "contract-go", cfg.TokenLabel)
+	assert.Equal(t, MechanismRSAOAEP, cfg.Mechanism)
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	_, ok := ConfigFromEnv()
+	assert.False(t, ok)
+
+	t.Setenv(KeyEnvModule, "/usr/lib/softhsm/libsofthsm2.so")
+	t.Setenv(KeyEnvKeyLabel, "hpcr-signing-key")
+
+	cfg, ok := ConfigFromEnv()
+	assert.True(t, ok)
+	assert.Equal(t, "hpcr-signing-key", cfg.KeyLabel)
+}
@@ -0,0 +1,192 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build cgo
+
+package pkcs11
+
+import (
+	"encoding/asn1"
+	"fmt"
+	"math/big"
+
+	IOE "github.com/IBM/fp-go/ioeither"
+	p11 "github.com/miekg/pkcs11"
+)
+
+// session opens and logs into the token described by cfg, returning the
+// context and session handle the caller must close/CloseSession when done.
+func session(cfg Config) (*p11.Ctx, p11.SessionHandle, error) {
+	ctx := p11.New(cfg.Module)
+	if ctx == nil {
+		return nil, 0, fmt.Errorf("pkcs11: unable to load module %q", cfg.Module)
+	}
+	if err := ctx.Initialize(); err != nil {
+		return nil, 0, err
+	}
+
+	slot, err := findSlot(ctx, cfg)
+	if err != nil {
+		ctx.Destroy()
+		return nil, 0, err
+	}
+
+	sh, err := ctx.OpenSession(slot, p11.CKF_SERIAL_SESSION|p11.CKF_RW_SESSION)
+	if err != nil {
+		ctx.Destroy()
+		return nil, 0, err
+	}
+	if err := ctx.Login(sh, p11.CKU_USER, cfg.Pin); err != nil {
+		ctx.CloseSession(sh)
+		ctx.Destroy()
+		return nil, 0, err
+	}
+	return ctx, sh, nil
+}
+
+func closeSession(ctx *p11.Ctx, sh p11.SessionHandle) {
+	ctx.Logout(sh)
+	ctx.CloseSession(sh)
+	ctx.Destroy()
+}
+
+func findSlot(ctx *p11.Ctx, cfg Config) (uint, error) {
+	if cfg.Slot != nil {
+		return *cfg.Slot, nil
+	}
+	slots, err := ctx.GetSlotList(true)
+	if err != nil {
+		return 0, err
+	}
+	for _, slot := range slots {
+		info, err := ctx.GetTokenInfo(slot)
+		if err != nil {
+			continue
+		}
+		if info.Label == cfg.TokenLabel {
+			return slot, nil
+		}
+	}
+	return 0, fmt.Errorf("pkcs11: no token with label %q", cfg.TokenLabel)
+}
+
+// findKey locates the private or secret key object identified by cfg's
+// key-label/key-id on an already open session.
+func findKey(ctx *p11.Ctx, sh p11.SessionHandle, class uint, cfg Config) (p11.ObjectHandle, error) {
+	tmpl := []*p11.Attribute{p11.NewAttribute(p11.CKA_CLASS, class)}
+	if cfg.KeyLabel != "" {
+		tmpl = append(tmpl, p11.NewAttribute(p11.CKA_LABEL, cfg.KeyLabel))
+	} else if cfg.KeyID != "" {
+		tmpl = append(tmpl, p11.NewAttribute(p11.CKA_ID, []byte(cfg.KeyID)))
+	}
+
+	if err := ctx.FindObjectsInit(sh, tmpl); err != nil {
+		return 0, err
+	}
+	defer ctx.FindObjectsFinal(sh)
+
+	objs, _, err := ctx.FindObjects(sh, 1)
+	if err != nil {
+		return 0, err
+	}
+	if len(objs) == 0 {
+		return 0, fmt.Errorf("pkcs11: no matching key found")
+	}
+	return objs[0], nil
+}
+
+func mechanismFor(mech Mechanism) (*p11.Mechanism, error) {
+	switch mech {
+	case MechanismRSAOAEP:
+		return p11.NewMechanism(p11.CKM_RSA_PKCS_OAEP, p11.NewOAEPParams(p11.CKM_SHA256, p11.CKG_MGF1_SHA256, p11.CKZ_DATA_SPECIFIED, nil)), nil
+	case MechanismRSAPKCS:
+		return p11.NewMechanism(p11.CKM_RSA_PKCS, nil), nil
+	case MechanismRSAPSS:
+		// the input is already a SHA-256 digest, so the HSM must not hash it again
+		return p11.NewMechanism(p11.CKM_RSA_PKCS_PSS, p11.NewPSSParams(p11.CKM_SHA256, p11.CKG_MGF1_SHA256, 32)), nil
+	case MechanismECDSA:
+		return p11.NewMechanism(p11.CKM_ECDSA, nil), nil
+	default:
+		return nil, fmt.Errorf("pkcs11: unsupported mechanism %q", mech)
+	}
+}
+
+// Decrypt decrypts ciphertext (the per-contract symmetric key, RSA-OAEP
+// wrapped) with the private key held on the HSM and returns the cleartext
+// key to the caller: the private key stays on the device, the unwrapped AES
+// key does not.
+func Decrypt(cfg Config, ciphertext []byte) IOE.IOEither[error, []byte] {
+	return IOE.TryCatchError(func() ([]byte, error) {
+		if err := cfg.Validate(); err != nil {
+			return nil, err
+		}
+
+		ctx, sh, err := session(cfg)
+		if err != nil {
+			return nil, err
+		}
+		defer closeSession(ctx, sh)
+
+		key, err := findKey(ctx, sh, p11.CKO_PRIVATE_KEY, cfg)
+		if err != nil {
+			return nil, err
+		}
+		mech, err := mechanismFor(cfg.Mechanism)
+		if err != nil {
+			return nil, err
+		}
+		if err := ctx.DecryptInit(sh, []*p11.Mechanism{mech}, key); err != nil {
+			return nil, err
+		}
+		return ctx.Decrypt(sh, ciphertext)
+	})
+}
+
+// Sign produces an HSM-backed signature over digest using cfg's mechanism,
+// satisfying the SignerVerifier.Sign shape.
+func Sign(cfg Config, digest []byte) IOE.IOEither[error, []byte] {
+	return IOE.TryCatchError(func() ([]byte, error) {
+		if err := cfg.Validate(); err != nil {
+			return nil, err
+		}
+
+		ctx, sh, err := session(cfg)
+		if err != nil {
+			return nil, err
+		}
+		defer closeSession(ctx, sh)
+
+		key, err := findKey(ctx, sh, p11.CKO_PRIVATE_KEY, cfg)
+		if err != nil {
+			return nil, err
+		}
+		mech, err := mechanismFor(cfg.Mechanism)
+		if err != nil {
+			return nil, err
+		}
+		if err := ctx.SignInit(sh, []*p11.Mechanism{mech}, key); err != nil {
+			return nil, err
+		}
+		sig, err := ctx.Sign(sh, digest)
+		if err != nil || cfg.Mechanism != MechanismECDSA {
+			return sig, err
+		}
+		// CKM_ECDSA yields raw r||s; encode it as ASN.1 like ecdsa.SignASN1
+		half := len(sig) / 2
+		return asn1.Marshal(struct{ R, S *big.Int }{
+			new(big.Int).SetBytes(sig[:half]),
+			new(big.Int).SetBytes(sig[half:]),
+		})
+	})
+}
@@ -0,0 +1,40 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !cgo
+
+// Package pkcs11's HSM backend needs cgo to link against the vendor's PKCS#11
+// module loader (github.com/miekg/pkcs11). This file stands in for
+// pkcs11helpers.go on non-cgo builds so the rest of contract-go still
+// compiles; every entry point fails at call time instead of link time.
+package pkcs11
+
+import (
+	"fmt"
+
+	E "github.com/IBM/fp-go/either"
+	IOE "github.com/IBM/fp-go/ioeither"
+)
+
+var errNoCgo = fmt.Errorf("pkcs11: built without cgo, HSM support is unavailable")
+
+// Decrypt always fails: see errNoCgo.
+func Decrypt(cfg Config, ciphertext []byte) IOE.IOEither[error, []byte] {
+	return IOE.FromEither(E.Left[[]byte](errNoCgo))
+}
+
+// Sign always fails: see errNoCgo.
+func Sign(cfg Config, digest []byte) IOE.IOEither[error, []byte] {
+	return IOE.FromEither(E.Left[[]byte](errNoCgo))
+}
@@ -0,0 +1,55 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package data
+
+// HPCRCABundle is the trust anchor used to validate certificates issued to
+// real Hyper Protect Container Runtime enclaves. It is distinct from
+// DefaultCertificate, which is a single test leaf certificate used
+// throughout this package's test suite, not something that should also
+// double as a root of trust.
+const HPCRCABundle = `-----BEGIN CERTIFICATE-----
+MIIFsTCCA5mgAwIBAgIUGFdYZafX0gHziSJLlcsw167emfUwDQYJKoZIhvcNAQEL
+BQAwaDELMAkGA1UEBhMCVVMxETAPBgNVBAoMCElCTSBDb3JwMSgwJgYDVQQLDB9I
+eXBlciBQcm90ZWN0IENvbnRhaW5lciBSdW50aW1lMRwwGgYDVQQDDBNJQk0gSFBD
+UiBJc3N1aW5nIENBMB4XDTI2MDcyNjExNDIxM1oXDTQ2MDcyMTExNDIxM1owaDEL
+MAkGA1UEBhMCVVMxETAPBgNVBAoMCElCTSBDb3JwMSgwJgYDVQQLDB9IeXBlciBQ
+cm90ZWN0IENvbnRhaW5lciBSdW50aW1lMRwwGgYDVQQDDBNJQk0gSFBDUiBJc3N1
+aW5nIENBMIICIjANBgkqhkiG9w0BAQEFAAOCAg8AMIICCgKCAgEAt3VPrX/ZbVaD
+tMSHNa98oobmWBkxiUa+khYFMDVAQ70PFpZoWGfdRuCsaelrou+Kx1qZeF7wXmc6
+22T2oaiZz3he8tDzhBSqMsYpNZMlW05zUGd8YmlyedXDQEdDmyzI21Cv3kh1jFpa
+HBp7X0Ywh6qraMGA+vqkKKeanmrigQwxv9B1hGdjvhNMshKuimHWJLr+98AGQJ64
+O6yj39VSC964EwiW9t+wfjvG73i+GbhmNDbNoeO8P3GVRjji9FNGIXfZ9IoUiVVh
+LIHFBbZdlRFyEo8AizRpQKMyDjww6f3NqEorBmrr0nNzFJsEU3Q7K4yf9l19R6Ai
+kBXjBUmE4LrsD8xFTNvFxarpJ2niouDBaiIiOBny5layHsmO13X//6qJuLUKxfki
+sce0Sq55y6iCj3SCYYTb1JIFvunLgwSuXMa7rP+JH5IDy3u3JSN6CG8NBi715z8y
+ri9lMFuW5Y4J3/Kd9o1T1wuMPSJHpUYiNfj5yXMfAMhKRPY83oKTdFEkiawc/tDJ
+b0FB8djJbo1fd3qA8rPzSGuZG4/SHUwqM8Gfln9UGtM2Tr07bW2WblIKKInVkAWs
++dW141NDR2Qkje7rGMT4xVipuYCOhaodDGtaY8l+XSQPTX6Jd59m/alvuAaTZFa1
+FKqVqQ2oeY7shehDpRdDhd4Le747qcsCAwEAAaNTMFEwHQYDVR0OBBYEFDjIIYso
+YZ9HZ/SaW45cBtsL3r8VMB8GA1UdIwQYMBaAFDjIIYsoYZ9HZ/SaW45cBtsL3r8V
+MA8GA1UdEwEB/wQFMAMBAf8wDQYJKoZIhvcNAQELBQADggIBACls08skPbHJAyEP
+Z2v0s1r1M9PVygHMLKOgdAh7ZgqQ82x6W/wRZsIbSXonB6W2GPJG2bdy5usuugHE
+rXqFav/HNJXAMUAR8Sf0+LEJzsqYP1v4mXCP+aFXVOX79wcIhkTaj0vjkKIHsS16
+B7so90HPKnzNRPpmgFpF8wn6ezUb6/OHoFSuaTt9jcn4VvhzdF3ejar0XwFZoyO7
+Y42oopzZns/74kOClSLhFETepNypan/kaf6f6s6a5RgehvAgCSK8aI6NrycpKl+3
+Ysu2sjPns9mME7ta5pSUC1C2lIrVapLXCFdYOSM5vZpq5g8yKjHXmbkgf1F8fL2S
+mOhnQRFDcs3ba3IMAfEvzlwEaoxftn3nUrUbOWkkQtvZl1o2KijZ+pG/L0QtqrFP
+UQzCOZt/nTHdCs9TPkO/g8JXbt5kAlsHtrOWo6HV1gjqsMHwdIFJgQxIA/ObD6vi
+ZVU03MF6DpmLDSq8BFDLvLfxjjCjqOJmSlpB/XdqESDxXCXYVv9hvl6pcJy0FTie
+Moo62nBp93je/jvpk/YhXyV9v0j9yRuG1GiwVz/Fi7DL80Nrawu52ifW3KAdzmVC
+r0ZWuqqJEzbbPDy5JCBcF76CY7k6mIGaN9c21wgPgNmtElwG6+KMCf0dd4SQxyUa
+H42Y6JjYoUetv/ifg/f0wrfy3DKq
+-----END CERTIFICATE-----
+`
@@ -0,0 +1,22 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package common holds the small set of constants and helpers shared across
+// the encrypt/ioeither OpenSSL- and crypto-backed signing paths, so neither
+// side hardcodes the other's conventions.
+package common
+
+// KeyEnvOpenSSL is the environment variable that, when set, overrides the
+// "openssl" binary resolved from $PATH.
+const KeyEnvOpenSSL = "HPCR_OPENSSL_PATH"
@@ -0,0 +1,250 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ioeither
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	IOE "github.com/IBM/fp-go/ioeither"
+	"golang.org/x/crypto/ocsp"
+
+	D "github.com/ibm-hyper-protect/contract-go/data"
+)
+
+// VerifyOptions configures VerifyCertificate.
+type VerifyOptions struct {
+	// Roots is the trust anchor pool to validate the chain against. The zero
+	// value uses DefaultRootPool, which embeds the IBM HPCR issuing CA
+	// bundle.
+	Roots *x509.CertPool
+	// CurrentTime overrides time.Now for NotBefore/NotAfter checks; tests
+	// use this to validate against a fixed instant.
+	CurrentTime time.Time
+	// AllowUnknownOCSP accepts an OCSP "unknown" status instead of treating
+	// it as a revocation.
+	AllowUnknownOCSP bool
+	// HTTPClient issues the OCSP request; defaults to http.DefaultClient.
+	HTTPClient *http.Client
+}
+
+// DefaultRootPool parses data.HPCRCABundle into an x509.CertPool, used
+// whenever VerifyOptions.Roots is nil.
+func DefaultRootPool() (*x509.CertPool, error) {
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM([]byte(D.HPCRCABundle)) {
+		return nil, fmt.Errorf("certverify: no certificates found in the default HPCR CA bundle")
+	}
+	return pool, nil
+}
+
+// VerifyCertificate parses certPEM, validates its chain against opts.Roots
+// (or DefaultRootPool), checks its validity window and, if it carries an
+// OCSP AIA URL, checks it has not been revoked. Contract encryptors call
+// this before encrypting to an HPCR enclave certificate so a revoked
+// certificate is rejected up front rather than silently trusted.
+func VerifyCertificate(certPEM []byte, opts VerifyOptions) IOE.IOEither[error, *x509.Certificate] {
+	return IOE.TryCatchError(func() (*x509.Certificate, error) {
+		cert, err := parseCertificatePEM(certPEM)
+		if err != nil {
+			return nil, err
+		}
+
+		roots := opts.Roots
+		if roots == nil {
+			roots, err = DefaultRootPool()
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		currentTime := opts.CurrentTime
+		if currentTime.IsZero() {
+			currentTime = time.Now()
+		}
+
+		if _, err := cert.Verify(x509.VerifyOptions{Roots: roots, CurrentTime: currentTime}); err != nil {
+			return nil, fmt.Errorf("certverify: chain validation failed: %w", err)
+		}
+
+		if err := checkOCSP(cert, opts); err != nil {
+			return nil, err
+		}
+
+		return cert, nil
+	})
+}
+
+func parseCertificatePEM(certPEM []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(certPEM)
+	if block == nil {
+		return nil, fmt.Errorf("certverify: no PEM block found")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+func checkOCSP(cert *x509.Certificate, opts VerifyOptions) error {
+	if len(cert.OCSPServer) == 0 {
+		return nil
+	}
+	if cert.Issuer.String() == cert.Subject.String() {
+		// self-signed/root: nothing to ask an OCSP responder about.
+		return nil
+	}
+
+	if cached, ok := ocspCache.get(cert.SerialNumber.String()); ok {
+		return evalOCSPStatus(cached, opts)
+	}
+
+	client := opts.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	issuer, err := issuerFromOpts(cert, opts)
+	if err != nil {
+		return err
+	}
+
+	req, err := ocsp.CreateRequest(cert, issuer, nil)
+	if err != nil {
+		return fmt.Errorf("certverify: building OCSP request: %w", err)
+	}
+
+	resp, err := fetchOCSPResponse(client, cert.OCSPServer[0], req, cert, issuer)
+	if err != nil {
+		return err
+	}
+
+	ocspCache.put(cert.SerialNumber.String(), resp)
+	return evalOCSPStatus(resp, opts)
+}
+
+func issuerFromOpts(cert *x509.Certificate, opts VerifyOptions) (*x509.Certificate, error) {
+	roots := opts.Roots
+	if roots == nil {
+		var err error
+		roots, err = DefaultRootPool()
+		if err != nil {
+			return nil, err
+		}
+	}
+	chains, err := cert.Verify(x509.VerifyOptions{Roots: roots, CurrentTime: opts.CurrentTime})
+	if err != nil || len(chains) == 0 || len(chains[0]) < 2 {
+		return nil, fmt.Errorf("certverify: unable to determine issuer for OCSP request")
+	}
+	return chains[0][1], nil
+}
+
+func fetchOCSPResponse(client *http.Client, url string, req []byte, cert, issuer *x509.Certificate) (*ocsp.Response, error) {
+	httpResp, err := client.Post(url, "application/ocsp-request", bytes.NewReader(req))
+	if err != nil {
+		return nil, fmt.Errorf("certverify: OCSP request failed: %w", err)
+	}
+	defer httpResp.Body.Close()
+
+	body, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("certverify: reading OCSP response: %w", err)
+	}
+
+	resp, err := ocsp.ParseResponseForCert(body, cert, issuer)
+	if err != nil {
+		return nil, fmt.Errorf("certverify: parsing OCSP response: %w", err)
+	}
+	return resp, nil
+}
+
+func evalOCSPStatus(resp *ocsp.Response, opts VerifyOptions) error {
+	switch resp.Status {
+	case ocsp.Good:
+		return nil
+	case ocsp.Revoked:
+		return fmt.Errorf("certverify: certificate was revoked at %s", resp.RevokedAt)
+	case ocsp.Unknown:
+		if opts.AllowUnknownOCSP {
+			return nil
+		}
+		return fmt.Errorf("certverify: OCSP responder does not know this certificate")
+	default:
+		return fmt.Errorf("certverify: unexpected OCSP status %d", resp.Status)
+	}
+}
+
+// ocspResponseCache is a tiny in-memory LRU keyed by certificate serial
+// number, caching OCSP responses for their NextUpdate window so a batch of
+// contracts signed to the same enclave certificate does not hit the OCSP
+// responder once per contract.
+type ocspResponseCache struct {
+	mu       sync.Mutex
+	capacity int
+	order    []string
+	entries  map[string]*ocsp.Response
+}
+
+const ocspCacheCapacity = 128
+
+var ocspCache = newOCSPResponseCache(ocspCacheCapacity)
+
+func newOCSPResponseCache(capacity int) *ocspResponseCache {
+	return &ocspResponseCache{
+		capacity: capacity,
+		entries:  make(map[string]*ocsp.Response, capacity),
+	}
+}
+
+func (c *ocspResponseCache) get(serial string) (*ocsp.Response, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	resp, ok := c.entries[serial]
+	if !ok {
+		return nil, false
+	}
+	for i, s := range c.order {
+		if s == serial {
+			c.order = append(c.order[:i], c.order[i+1:]...)
+			break
+		}
+	}
+	if !resp.NextUpdate.IsZero() && time.Now().After(resp.NextUpdate) {
+		delete(c.entries, serial)
+		return nil, false
+	}
+	// a hit becomes the most recently used entry, evicted last by put
+	c.order = append(c.order, serial)
+	return resp, true
+}
+
+func (c *ocspResponseCache) put(serial string, resp *ocsp.Response) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, exists := c.entries[serial]; !exists {
+		if len(c.order) >= c.capacity {
+			oldest := c.order[0]
+			c.order = c.order[1:]
+			delete(c.entries, oldest)
+		}
+		c.order = append(c.order, serial)
+	}
+	c.entries[serial] = resp
+}
@@ -0,0 +1,114 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ioeither
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	E "github.com/IBM/fp-go/either"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/ocsp"
+)
+
+// testCertChain generates an ephemeral root CA and a leaf certificate it
+// signs, so VerifyCertificate's chain validation can be exercised against a
+// real chain of trust instead of re-parsing the same certificate as both
+// the subject and the root.
+func testCertChain(t *testing.T) (rootPool *x509.CertPool, leafPEM []byte) {
+	rootKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	rootTmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test root CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+	}
+	rootDER, err := x509.CreateCertificate(rand.Reader, rootTmpl, rootTmpl, &rootKey.PublicKey, rootKey)
+	assert.NoError(t, err)
+	rootCert, err := x509.ParseCertificate(rootDER)
+	assert.NoError(t, err)
+
+	leafKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	leafTmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "test enclave leaf"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	leafDER, err := x509.CreateCertificate(rand.Reader, leafTmpl, rootCert, &leafKey.PublicKey, rootKey)
+	assert.NoError(t, err)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(rootCert)
+
+	return pool, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: leafDER})
+}
+
+func TestVerifyCertificateValidatesAgainstItsIssuer(t *testing.T) {
+	rootPool, leafPEM := testCertChain(t)
+
+	certE := VerifyCertificate(leafPEM, VerifyOptions{Roots: rootPool})()
+	assert.NoError(t, E.ToError(certE))
+}
+
+func TestVerifyCertificateRejectsUntrustedIssuer(t *testing.T) {
+	_, leafPEM := testCertChain(t)
+	otherPool, _ := testCertChain(t)
+
+	certE := VerifyCertificate(leafPEM, VerifyOptions{Roots: otherPool})()
+	assert.True(t, E.IsLeft(certE))
+}
+
+func TestDefaultRootPoolParses(t *testing.T) {
+	pool, err := DefaultRootPool()
+	assert.NoError(t, err)
+	assert.NotNil(t, pool)
+}
+
+func TestOCSPCacheExpiresPastNextUpdate(t *testing.T) {
+	cache := newOCSPResponseCache(2)
+
+	resp := &ocsp.Response{NextUpdate: time.Now().Add(-time.Minute)}
+	cache.put("123", resp)
+
+	_, ok := cache.get("123")
+	assert.False(t, ok)
+}
+
+func TestOCSPCacheEvictsOldestWhenFull(t *testing.T) {
+	cache := newOCSPResponseCache(1)
+
+	cache.put("first", &ocsp.Response{})
+	cache.put("second", &ocsp.Response{})
+
+	_, ok := cache.get("first")
+	assert.False(t, ok)
+
+	_, ok = cache.get("second")
+	assert.True(t, ok)
+}
@@ -0,0 +1,152 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ioeither
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	E "github.com/IBM/fp-go/either"
+	F "github.com/IBM/fp-go/function"
+	IOE "github.com/IBM/fp-go/ioeither"
+)
+
+// dssePAEPrefix is the fixed type tag of the DSSE pre-authentication
+// encoding, see https://github.com/secure-systems-lab/dsse/blob/master/protocol.md
+const dssePAEPrefix = "DSSEv1"
+
+// Signature is a single signature entry inside a DSSE Envelope, carrying the
+// key ID of the signer alongside the base64 encoded signature bytes.
+type Signature struct {
+	KeyID string `json:"keyid"`
+	Sig   string `json:"sig"`
+}
+
+// Envelope is a Dead Simple Signing Envelope wrapping a contract payload.
+// Unlike the plain `hyper-protect-basic.<enc>.<enc>` token, an Envelope can
+// carry more than one Signature, so a contract can be co-signed by multiple
+// keys without changing the wire format.
+type Envelope struct {
+	Payload     string      `json:"payload"`
+	PayloadType string      `json:"payloadType"`
+	Signatures  []Signature `json:"signatures"`
+}
+
+// preAuthEncode builds the byte sequence that is actually signed/verified,
+// the DSSE "PAE": "DSSEv1" SP len(payloadType) SP payloadType SP len(payload) SP payload
+func preAuthEncode(payloadType string, payload []byte) []byte {
+	return []byte(fmt.Sprintf("%s %d %s %d %s", dssePAEPrefix, len(payloadType), payloadType, len(payload), payload))
+}
+
+// SignDSSE wraps payload in a DSSE Envelope of the given payloadType, signing
+// the SHA-256 digest of its PAE encoding with signDigest (the same "already
+// hashed" contract signer_test.go exercises against SignerVerifier.Sign) and
+// recording keyID alongside the resulting signature.
+func SignDSSE(
+	signDigest func([]byte) IOE.IOEither[error, []byte],
+	keyID string,
+	payloadType string,
+	payload []byte,
+) IOE.IOEither[error, Envelope] {
+	digest := sha256.Sum256(preAuthEncode(payloadType, payload))
+	return F.Pipe1(
+		signDigest(digest[:]),
+		IOE.Map[error](func(sig []byte) Envelope {
+			return Envelope{
+				Payload:     base64.StdEncoding.EncodeToString(payload),
+				PayloadType: payloadType,
+				Signatures: []Signature{{
+					KeyID: keyID,
+					Sig:   base64.StdEncoding.EncodeToString(sig),
+				}},
+			}
+		}),
+	)
+}
+
+// VerifyDSSE checks env against verifyDigest, accepting the envelope as soon
+// as any one of its Signatures verifies against the PAE encoding of the
+// embedded payload, and returns the decoded payload on success.
+func VerifyDSSE(
+	verifyDigest func(digest []byte, sig []byte) IOE.IOEither[error, bool],
+	env Envelope,
+) IOE.IOEither[error, []byte] {
+	return F.Pipe1(
+		IOE.TryCatchError(func() ([]byte, error) {
+			return base64.StdEncoding.DecodeString(env.Payload)
+		}),
+		IOE.Chain(func(payload []byte) IOE.IOEither[error, []byte] {
+			digest := sha256.Sum256(preAuthEncode(env.PayloadType, payload))
+			return F.Pipe1(
+				verifyAnySignature(verifyDigest, digest[:], env.Signatures),
+				IOE.Map[error](func(bool) []byte {
+					return payload
+				}),
+			)
+		}),
+	)
+}
+
+// verifyAnySignature succeeds as soon as one signature verifies, and
+// otherwise fails with the error produced by the last attempt.
+func verifyAnySignature(
+	verifyDigest func(digest []byte, sig []byte) IOE.IOEither[error, bool],
+	digest []byte,
+	sigs []Signature,
+) IOE.IOEither[error, bool] {
+	return func() E.Either[error, bool] {
+		err := fmt.Errorf("DSSE envelope carries no signatures")
+		for _, s := range sigs {
+			raw, decErr := base64.StdEncoding.DecodeString(s.Sig)
+			if decErr != nil {
+				err = decErr
+				continue
+			}
+			verErr := E.Fold(
+				func(e error) error { return e },
+				func(ok bool) error {
+					if !ok {
+						return fmt.Errorf("signature by keyid %q did not verify", s.KeyID)
+					}
+					return nil
+				},
+			)(verifyDigest(digest, raw)())
+			if verErr != nil {
+				err = verErr
+				continue
+			}
+			return E.Of[error](true)
+		}
+		return E.Left[bool](err)
+	}
+}
+
+// MarshalEnvelope serializes env to its JSON wire format.
+func MarshalEnvelope(env Envelope) E.Either[error, []byte] {
+	return E.Eitherize0(func() ([]byte, error) {
+		return json.Marshal(env)
+	})()
+}
+
+// UnmarshalEnvelope parses data into a DSSE Envelope.
+func UnmarshalEnvelope(data []byte) E.Either[error, Envelope] {
+	return E.Eitherize0(func() (Envelope, error) {
+		var env Envelope
+		err := json.Unmarshal(data, &env)
+		return env, err
+	})()
+}
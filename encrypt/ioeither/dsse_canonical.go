@@ -0,0 +1,41 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ioeither
+
+import (
+	F "github.com/IBM/fp-go/function"
+	IOE "github.com/IBM/fp-go/ioeither"
+	"github.com/ibm-hyper-protect/contract-go/canonicaljson"
+)
+
+// SignDSSEContract canonicalizes contract with canonicaljson before handing
+// it to SignDSSE, so that two semantically equal contract maps - regardless
+// of Go's randomized map iteration order - always produce a byte-identical
+// signed payload. SignContract is the real caller of this in production.
+func SignDSSEContract(
+	signDigest func([]byte) IOE.IOEither[error, []byte],
+	keyID string,
+	payloadType string,
+	contract map[string]any,
+) IOE.IOEither[error, Envelope] {
+	return F.Pipe1(
+		IOE.TryCatchError(func() ([]byte, error) {
+			return canonicaljson.Marshal(contract)
+		}),
+		IOE.Chain(func(payload []byte) IOE.IOEither[error, Envelope] {
+			return SignDSSE(signDigest, keyID, payloadType, payload)
+		}),
+	)
+}
@@ -0,0 +1,62 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ioeither
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	E "github.com/IBM/fp-go/either"
+	F "github.com/IBM/fp-go/function"
+	IOE "github.com/IBM/fp-go/ioeither"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSignDSSEContractIsOrderIndependent checks that two contract maps with
+// the same entries inserted in a different order sign to the same payload,
+// which is only true because of the canonicaljson pre-hook.
+func TestSignDSSEContractIsOrderIndependent(t *testing.T) {
+	signDigest := func(digest []byte) IOE.IOEither[error, []byte] {
+		return IOE.Of[error](digest)
+	}
+
+	a := map[string]any{"workload": "x", "env": "y", "auth": "z"}
+	b := map[string]any{"auth": "z", "env": "y", "workload": "x"}
+
+	envAE := SignDSSEContract(signDigest, "test-key", "application/vnd.ibm.hpcr.contract+json", a)()
+	envBE := SignDSSEContract(signDigest, "test-key", "application/vnd.ibm.hpcr.contract+json", b)()
+
+	payloadA := F.Pipe1(envAE, E.Map[error](func(env Envelope) string { return env.Payload }))
+	payloadB := F.Pipe1(envBE, E.Map[error](func(env Envelope) string { return env.Payload }))
+
+	assert.Equal(t, payloadA, payloadB)
+}
+
+// TestSignContractIsOrderIndependent checks the same property through the
+// real production entry point, SignContract, rather than calling
+// SignDSSEContract directly.
+func TestSignContractIsOrderIndependent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "key.priv")
+	assert.NoError(t, os.WriteFile(path, writeTestPrivateKey(t), 0600))
+
+	a := map[string]any{"workload": "x", "env": "y", "auth": "z"}
+	b := map[string]any{"auth": "z", "env": "y", "workload": "x"}
+
+	payloadA := F.Pipe1(SignContract(path, a)(), E.Map[error](func(env Envelope) string { return env.Payload }))
+	payloadB := F.Pipe1(SignContract(path, b)(), E.Map[error](func(env Envelope) string { return env.Payload }))
+
+	assert.Equal(t, payloadA, payloadB)
+}
@@ -0,0 +1,113 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ioeither
+
+import (
+	"testing"
+
+	E "github.com/IBM/fp-go/either"
+	F "github.com/IBM/fp-go/function"
+	IOE "github.com/IBM/fp-go/ioeither"
+	"github.com/stretchr/testify/assert"
+)
+
+func dsseSignVerify(
+	signDigest func([]byte) IOE.IOEither[error, []byte],
+	verifyDigest func([]byte, []byte) IOE.IOEither[error, bool],
+	keyID string,
+) func(t *testing.T) {
+	return func(t *testing.T) {
+		payload := []byte(`{"workload":{}}`)
+
+		envE := F.Pipe1(
+			SignDSSE(signDigest, keyID, "application/vnd.ibm.hpcr.contract+json", payload),
+			IOE.Map[error](func(env Envelope) Envelope { return env }),
+		)()
+		assert.True(t, E.IsRight(envE))
+
+		env := E.Fold(
+			func(error) Envelope { return Envelope{} },
+			F.Identity[Envelope],
+		)(envE)
+
+		assert.Len(t, env.Signatures, 1)
+		assert.Equal(t, keyID, env.Signatures[0].KeyID)
+
+		decodedE := VerifyDSSE(verifyDigest, env)()
+		assert.Equal(t, E.Of[error](payload), decodedE)
+	}
+}
+
+// TestSignDSSECrypto checks that an envelope produced and verified entirely via
+// the crypto APIs round-trips.
+func TestSignDSSECrypto(t *testing.T) {
+	keyE := CryptoPrivateKey()
+	fpE := F.Pipe1(keyE, E.Chain(CryptoPrivKeyFingerprint))
+
+	keyID := E.Fold(
+		func(error) string { return "" },
+		F.Identity[string],
+	)(fpE)
+
+	signDigest := func(digest []byte) IOE.IOEither[error, []byte] {
+		return F.Pipe1(
+			IOE.FromEither(keyE),
+			IOE.Chain(func(priv []byte) IOE.IOEither[error, []byte] {
+				return IOE.FromEither(CryptoSignDigest(priv, digest))
+			}),
+		)
+	}
+	verifyDigest := func(digest []byte, sig []byte) IOE.IOEither[error, bool] {
+		return F.Pipe1(
+			IOE.FromEither(keyE),
+			IOE.Chain(func(priv []byte) IOE.IOEither[error, bool] {
+				return IOE.FromEither(CryptoVerifyDigest(priv, digest, sig))
+			}),
+		)
+	}
+
+	dsseSignVerify(signDigest, verifyDigest, keyID)(t)
+}
+
+// TestSignDSSEOpenSSLCryptoParity checks that an envelope signed via OpenSSL
+// verifies via the crypto path, mirroring TestOpenSSLCryptoSignature.
+func TestSignDSSEOpenSSLCryptoParity(t *testing.T) {
+	keyE := OpenSSLPrivateKey()
+	fpE := F.Pipe1(keyE, E.Chain(OpenSSLPrivKeyFingerprint))
+
+	keyID := E.Fold(
+		func(error) string { return "" },
+		F.Identity[string],
+	)(fpE)
+
+	signDigest := func(digest []byte) IOE.IOEither[error, []byte] {
+		return F.Pipe1(
+			IOE.FromEither(keyE),
+			IOE.Chain(func(priv []byte) IOE.IOEither[error, []byte] {
+				return IOE.FromEither(OpenSSLSignDigest(priv, digest))
+			}),
+		)
+	}
+	verifyDigest := func(digest []byte, sig []byte) IOE.IOEither[error, bool] {
+		return F.Pipe1(
+			IOE.FromEither(keyE),
+			IOE.Chain(func(priv []byte) IOE.IOEither[error, bool] {
+				return IOE.FromEither(CryptoVerifyDigest(priv, digest, sig))
+			}),
+		)
+	}
+
+	dsseSignVerify(signDigest, verifyDigest, keyID)(t)
+}
@@ -0,0 +1,102 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ioeither
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+
+	F "github.com/IBM/fp-go/function"
+	IOE "github.com/IBM/fp-go/ioeither"
+
+	"github.com/ibm-hyper-protect/contract-go/canonicaljson"
+)
+
+const contractKeyLen = 32
+
+// rsaOAEPAESEncrypter builds the Encrypter WithVerifiedCertificate wraps for
+// EncryptContract: a random AES-256-GCM key seals the payload, and certPEM's
+// RSA public key seals that key, mirroring the two-segment shape of the
+// legacy `hyper-protect-basic.<enc-key>.<enc-payload>` token.
+func rsaOAEPAESEncrypter(certPEM []byte) func([]byte) IOE.IOEither[error, string] {
+	return func(payload []byte) IOE.IOEither[error, string] {
+		return IOE.TryCatchError(func() (string, error) {
+			cert, err := parseCertificatePEM(certPEM)
+			if err != nil {
+				return "", err
+			}
+			pub, ok := cert.PublicKey.(*rsa.PublicKey)
+			if !ok {
+				return "", fmt.Errorf("encrypt: certificate does not carry an RSA public key")
+			}
+
+			key := make([]byte, contractKeyLen)
+			if _, err := rand.Read(key); err != nil {
+				return "", err
+			}
+
+			block, err := aes.NewCipher(key)
+			if err != nil {
+				return "", err
+			}
+			gcm, err := cipher.NewGCM(block)
+			if err != nil {
+				return "", err
+			}
+			nonce := make([]byte, gcm.NonceSize())
+			if _, err := rand.Read(nonce); err != nil {
+				return "", err
+			}
+			sealed := gcm.Seal(nonce, nonce, payload, nil)
+
+			encKey, err := rsa.EncryptOAEP(sha256.New(), rand.Reader, pub, key, nil)
+			if err != nil {
+				return "", err
+			}
+
+			return fmt.Sprintf("hyper-protect-basic.%s.%s",
+				base64.StdEncoding.EncodeToString(encKey),
+				base64.StdEncoding.EncodeToString(sealed),
+			), nil
+		})
+	}
+}
+
+// EncryptContract canonicalizes contract and encrypts it to certPEM, but
+// only after VerifyCertificate (via WithVerifiedCertificate) has validated
+// certPEM's chain and, if present, its OCSP status. A revoked or otherwise
+// untrusted HPCR enclave certificate never reaches rsaOAEPAESEncrypter.
+func EncryptContract(certPEM []byte, contract map[string]any) IOE.IOEither[error, string] {
+	verify := WithVerifiedCertificate(VerifyOptions{}, rsaOAEPAESEncrypter)
+
+	return F.Pipe1(
+		IOE.TryCatchError(func() ([]byte, error) {
+			return canonicaljson.Marshal(contract)
+		}),
+		IOE.Chain(func(payload []byte) IOE.IOEither[error, string] {
+			return F.Pipe1(
+				verify(certPEM),
+				IOE.Chain(func(encrypt func([]byte) IOE.IOEither[error, string]) IOE.IOEither[error, string] {
+					return encrypt(payload)
+				}),
+			)
+		}),
+	)
+}
@@ -0,0 +1,59 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ioeither
+
+import (
+	"strings"
+	"testing"
+
+	E "github.com/IBM/fp-go/either"
+	F "github.com/IBM/fp-go/function"
+	IOE "github.com/IBM/fp-go/ioeither"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncryptContractRejectsUntrustedCertificate(t *testing.T) {
+	_, leafPEM := testCertChain(t)
+
+	tokenE := EncryptContract(leafPEM, map[string]any{"workload": "x"})()
+	assert.True(t, E.IsLeft(tokenE))
+}
+
+// TestEncryptContractSucceedsForVerifiedCertificate exercises EncryptContract's
+// encryption step directly against a trusted test root, since the production
+// default root pool cannot be re-signed inside a unit test.
+func TestEncryptContractSucceedsForVerifiedCertificate(t *testing.T) {
+	rootPool, leafPEM := testCertChain(t)
+
+	verify := WithVerifiedCertificate(VerifyOptions{Roots: rootPool}, rsaOAEPAESEncrypter)
+	tokenIOE := F.Pipe1(
+		verify(leafPEM),
+		IOE.Chain(func(encrypt func([]byte) IOE.IOEither[error, string]) IOE.IOEither[error, string] {
+			return encrypt([]byte(`{"workload":"x"}`))
+		}),
+	)
+
+	tokenE := tokenIOE()
+	assert.True(t, E.IsRight(tokenE))
+
+	token := F.Pipe1(
+		tokenE,
+		E.Fold(
+			func(error) string { return "" },
+			F.Identity[string],
+		),
+	)
+	assert.True(t, strings.HasPrefix(token, "hyper-protect-basic."))
+}
@@ -0,0 +1,41 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ioeither
+
+import (
+	"crypto/x509"
+
+	F "github.com/IBM/fp-go/function"
+	IOE "github.com/IBM/fp-go/ioeither"
+)
+
+// WithVerifiedCertificate wraps an encrypter factory (such as the one
+// createEncryptBasic builds from a raw public key/certificate) so that,
+// given the certificate PEM a contract is being encrypted to, it first runs
+// VerifyCertificate and only constructs the encrypter once the chain
+// validates and OCSP (if present) reports the certificate as not revoked.
+func WithVerifiedCertificate(
+	opts VerifyOptions,
+	makeEncrypter func([]byte) func([]byte) IOE.IOEither[error, string],
+) func([]byte) IOE.IOEither[error, func([]byte) IOE.IOEither[error, string]] {
+	return func(certPEM []byte) IOE.IOEither[error, func([]byte) IOE.IOEither[error, string]] {
+		return F.Pipe1(
+			VerifyCertificate(certPEM, opts),
+			IOE.Map[error](func(*x509.Certificate) func([]byte) IOE.IOEither[error, string] {
+				return makeEncrypter(certPEM)
+			}),
+		)
+	}
+}
@@ -0,0 +1,45 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ioeither
+
+import (
+	"encoding/pem"
+	"os"
+
+	F "github.com/IBM/fp-go/function"
+	IOE "github.com/IBM/fp-go/ioeither"
+	FIOE "github.com/IBM/fp-go/ioeither/file"
+	"github.com/ibm-hyper-protect/contract-go/encrypt/ioeither/encrypted"
+)
+
+// ReadPrivateKeyFile loads the PEM file at path, transparently decrypting an
+// encrypted.PEMType file with the HPCR_KEY_PASSPHRASE environment variable if
+// it is set, so PrivateKeySignerVerifier can consume either plaintext or
+// encrypted.PEMType key files without the caller having to know which.
+func ReadPrivateKeyFile(path string) IOE.IOEither[error, []byte] {
+	passphrase, ok := os.LookupEnv(encrypted.KeyEnvPassphrase)
+	if !ok {
+		return FIOE.ReadFile(path)
+	}
+	return F.Pipe1(
+		FIOE.ReadFile(path),
+		IOE.Chain(func(data []byte) IOE.IOEither[error, []byte] {
+			if block, _ := pem.Decode(data); block == nil || block.Type != encrypted.PEMType {
+				return IOE.Of[error](data)
+			}
+			return encrypted.LoadEncryptedPrivateKey(path, passphrase)
+		}),
+	)
+}
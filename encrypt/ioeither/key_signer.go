@@ -0,0 +1,33 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ioeither
+
+import (
+	F "github.com/IBM/fp-go/function"
+	IOE "github.com/IBM/fp-go/ioeither"
+)
+
+// PrivateKeySignerVerifier loads the contract-signing key at path - through
+// ReadPrivateKeyFile, so an HPCR_KEY_PASSPHRASE-protected key is decrypted
+// transparently - and parses it into a SignerVerifier via
+// privKeyToSignerVerifier. This is the production entry point callers use to
+// obtain something SignDSSE/SignDSSEContract can sign with; it replaces
+// reaching for a raw RSA key directly.
+func PrivateKeySignerVerifier(path string) IOE.IOEither[error, SignerVerifier] {
+	return F.Pipe1(
+		ReadPrivateKeyFile(path),
+		IOE.ChainEitherK(privKeyToSignerVerifier),
+	)
+}
@@ -0,0 +1,62 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ioeither
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	E "github.com/IBM/fp-go/either"
+	"github.com/ibm-hyper-protect/contract-go/encrypt/ioeither/encrypted"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeTestPrivateKey(t *testing.T) []byte {
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	assert.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+}
+
+func TestPrivateKeySignerVerifierPlaintext(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "key.priv")
+	assert.NoError(t, os.WriteFile(path, writeTestPrivateKey(t), 0600))
+
+	svE := PrivateKeySignerVerifier(path)()
+	assert.True(t, E.IsRight(svE))
+}
+
+// TestPrivateKeySignerVerifierEncrypted checks that PrivateKeySignerVerifier
+// transparently goes through the encrypted key loader when
+// HPCR_KEY_PASSPHRASE is set, rather than requiring callers to know the key
+// file is password protected.
+func TestPrivateKeySignerVerifierEncrypted(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "key.priv.enc")
+	passphrase := "correct horse battery staple"
+
+	saveE := encrypted.SaveEncryptedPrivateKey(path, writeTestPrivateKey(t), passphrase)()
+	assert.True(t, E.IsRight(saveE))
+
+	t.Setenv(encrypted.KeyEnvPassphrase, passphrase)
+
+	svE := PrivateKeySignerVerifier(path)()
+	assert.True(t, E.IsRight(svE))
+}
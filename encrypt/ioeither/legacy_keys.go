@@ -0,0 +1,231 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ioeither
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	E "github.com/IBM/fp-go/either"
+)
+
+const legacyRSAKeyBits = 2048
+
+// CryptoPrivateKey generates a fresh RSA-2048 key entirely via crypto/rsa,
+// PEM encoded as PKCS#8 - the same shape privKeyToSignerVerifier parses.
+func CryptoPrivateKey() E.Either[error, []byte] {
+	return E.Eitherize0(func() ([]byte, error) {
+		priv, err := rsa.GenerateKey(rand.Reader, legacyRSAKeyBits)
+		if err != nil {
+			return nil, err
+		}
+		return marshalPKCS8PrivateKey(priv)
+	})()
+}
+
+// OpenSSLPrivateKey generates a fresh RSA-2048 key by shelling out to
+// `openssl genpkey`, which emits the same PKCS#8 PEM shape as CryptoPrivateKey.
+func OpenSSLPrivateKey() E.Either[error, []byte] {
+	return E.Eitherize0(func() ([]byte, error) {
+		var stdout, stderr bytes.Buffer
+		cmd := exec.Command(opensslPath(), "genpkey",
+			"-algorithm", "RSA",
+			"-pkeyopt", fmt.Sprintf("rsa_keygen_bits:%d", legacyRSAKeyBits),
+		)
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+		if err := cmd.Run(); err != nil {
+			return nil, fmt.Errorf("openssl genpkey: %w: %s", err, stderr.String())
+		}
+		return stdout.Bytes(), nil
+	})()
+}
+
+// CryptoPublicKey derives the PKIX/PEM public key matching priv, a PKCS#8
+// encoded RSA private key as produced by CryptoPrivateKey/OpenSSLPrivateKey.
+func CryptoPublicKey(priv []byte) E.Either[error, []byte] {
+	return E.Eitherize0(func() ([]byte, error) {
+		key, err := parsePKCS8RSAPrivateKey(priv)
+		if err != nil {
+			return nil, err
+		}
+		return marshalPKIXPublicKey(&key.PublicKey)
+	})()
+}
+
+// OpenSSLPublicKey is the OpenSSL-shelling counterpart of CryptoPublicKey,
+// using `openssl pkey -pubout`.
+func OpenSSLPublicKey(priv []byte) E.Either[error, []byte] {
+	return E.Eitherize0(func() ([]byte, error) {
+		dir, err := os.MkdirTemp("", "contract-go-pubkey-")
+		if err != nil {
+			return nil, err
+		}
+		defer os.RemoveAll(dir)
+
+		keyPath := filepath.Join(dir, "key.pem")
+		if err := os.WriteFile(keyPath, priv, 0600); err != nil {
+			return nil, err
+		}
+
+		var stdout, stderr bytes.Buffer
+		cmd := exec.Command(opensslPath(), "pkey", "-in", keyPath, "-pubout")
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+		if err := cmd.Run(); err != nil {
+			return nil, fmt.Errorf("openssl pkey -pubout: %w: %s", err, stderr.String())
+		}
+		return stdout.Bytes(), nil
+	})()
+}
+
+// CryptoPrivKeyFingerprint derives priv's key ID the same way
+// keyIDFromPublicKey does: the hex SHA-256 digest of the public key's PKIX
+// DER encoding.
+func CryptoPrivKeyFingerprint(priv []byte) E.Either[error, string] {
+	return E.Eitherize0(func() (string, error) {
+		key, err := parsePKCS8RSAPrivateKey(priv)
+		if err != nil {
+			return "", err
+		}
+		return fingerprintPublicKey(&key.PublicKey)
+	})()
+}
+
+// OpenSSLPrivKeyFingerprint derives priv's public key via OpenSSLPublicKey
+// and fingerprints it the same way CryptoPrivKeyFingerprint does, so the two
+// agree for any given key.
+func OpenSSLPrivKeyFingerprint(priv []byte) E.Either[error, string] {
+	return E.Eitherize0(func() (string, error) {
+		pubPEM, err := eitherToValue(OpenSSLPublicKey(priv))
+		if err != nil {
+			return "", err
+		}
+		block, _ := pem.Decode(pubPEM)
+		if block == nil {
+			return "", fmt.Errorf("unable to decode PEM block")
+		}
+		pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return "", err
+		}
+		rsaPub, ok := pub.(*rsa.PublicKey)
+		if !ok {
+			return "", fmt.Errorf("expected an RSA public key, got %T", pub)
+		}
+		return fingerprintPublicKey(rsaPub)
+	})()
+}
+
+// CryptoSignDigest signs digest with priv (a PKCS#8 RSA private key) using
+// RSA-PSS/SHA-256, the same scheme rsaSignerVerifier.Sign uses.
+func CryptoSignDigest(priv []byte, digest []byte) E.Either[error, []byte] {
+	return E.Eitherize0(func() ([]byte, error) {
+		key, err := parsePKCS8RSAPrivateKey(priv)
+		if err != nil {
+			return nil, err
+		}
+		return rsa.SignPSS(rand.Reader, key, crypto.SHA256, digest, nil)
+	})()
+}
+
+// CryptoVerifyDigest verifies sig against digest using priv's public half,
+// mirroring rsaSignerVerifier.Verify.
+func CryptoVerifyDigest(priv []byte, digest []byte, sig []byte) E.Either[error, bool] {
+	return E.Eitherize0(func() (bool, error) {
+		key, err := parsePKCS8RSAPrivateKey(priv)
+		if err != nil {
+			return false, err
+		}
+		err = rsa.VerifyPSS(&key.PublicKey, crypto.SHA256, digest, sig, nil)
+		return err == nil, err
+	})()
+}
+
+// OpenSSLSignDigest signs digest with priv by shelling out to
+// `openssl pkeyutl -sign`, hardcoding the RSA-PSS flags OpenSSLSignDigestAlgo
+// generalizes.
+func OpenSSLSignDigest(priv []byte, digest []byte) E.Either[error, []byte] {
+	return E.Eitherize0(func() ([]byte, error) {
+		opts, err := opensslSigOpts(AlgorithmRSAPSS)
+		if err != nil {
+			return nil, err
+		}
+		return runOpenSSLSign(AlgorithmRSAPSS, opts, priv, digest)
+	})()
+}
+
+// eitherToValue unpacks an Either produced by another function in this file
+// back into a (value, error) pair for sequential composition.
+func eitherToValue[A any](e E.Either[error, A]) (A, error) {
+	var val A
+	var err error
+	E.Fold(
+		func(e2 error) any { err = e2; return nil },
+		func(v A) any { val = v; return nil },
+	)(e)
+	return val, err
+}
+
+func marshalPKCS8PrivateKey(priv *rsa.PrivateKey) ([]byte, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
+
+func marshalPKIXPublicKey(pub *rsa.PublicKey) ([]byte, error) {
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), nil
+}
+
+func parsePKCS8RSAPrivateKey(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("unable to decode PEM block")
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("expected an RSA private key, got %T", key)
+	}
+	return rsaKey, nil
+}
+
+func fingerprintPublicKey(pub *rsa.PublicKey) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(der)
+	return hex.EncodeToString(sum[:]), nil
+}
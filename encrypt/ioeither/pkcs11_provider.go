@@ -0,0 +1,41 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ioeither
+
+import (
+	"encoding/base64"
+
+	IOE "github.com/IBM/fp-go/ioeither"
+	"github.com/ibm-hyper-protect/contract-go/crypto/pkcs11"
+)
+
+// HasPKCS11Config reports whether HPCR_PKCS11_MODULE is set, the signal
+// DefaultSignerVerifier uses to prefer the HSM over a PEM key file.
+// DefaultEncryption does not consult it.
+func HasPKCS11Config() (pkcs11.Config, bool) {
+	return pkcs11.ConfigFromEnv()
+}
+
+// PKCS11DecryptBasic adapts pkcs11.Decrypt to the Decrypter shape so it can
+// sit ahead of OpenSSLDecryptBasic/CryptoDecryptBasic in DefaultEncryption.
+func PKCS11DecryptBasic(cfg pkcs11.Config) func(string) IOE.IOEither[error, []byte] {
+	return func(ciphertext string) IOE.IOEither[error, []byte] {
+		raw, err := base64.StdEncoding.DecodeString(ciphertext)
+		if err != nil {
+			return IOE.Left[[]byte](err)
+		}
+		return pkcs11.Decrypt(cfg, raw)
+	}
+}
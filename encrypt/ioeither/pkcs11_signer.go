@@ -0,0 +1,62 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ioeither
+
+import (
+	"fmt"
+
+	IOE "github.com/IBM/fp-go/ioeither"
+	"github.com/ibm-hyper-protect/contract-go/crypto/pkcs11"
+)
+
+// pkcs11SignerVerifier adapts a pkcs11.Config to SignerVerifier, so contract
+// signing can transparently use an HSM-resident key instead of a key parsed
+// from a PEM file.
+type pkcs11SignerVerifier struct {
+	cfg pkcs11.Config
+}
+
+func (s pkcs11SignerVerifier) Sign(digest []byte) IOE.IOEither[error, []byte] {
+	return pkcs11.Sign(s.cfg, digest)
+}
+
+// Verify is not implemented: verification never needs the HSM, since it
+// only needs the public half of the key, which pubOrCertToSignerVerifier
+// already handles from the certificate being verified against.
+func (s pkcs11SignerVerifier) Verify(digest []byte, sig []byte) IOE.IOEither[error, bool] {
+	return IOE.Left[bool](fmt.Errorf("pkcs11: verification is not supported through the HSM provider, use pubOrCertToSignerVerifier"))
+}
+
+func (s pkcs11SignerVerifier) KeyID() string {
+	if s.cfg.KeyLabel != "" {
+		return s.cfg.KeyLabel
+	}
+	return s.cfg.KeyID
+}
+
+func (s pkcs11SignerVerifier) Algorithm() string {
+	return string(s.cfg.Mechanism)
+}
+
+// DefaultSignerVerifier resolves the contract-signing key the same way
+// DefaultEncryption resolves the decryption key: if HPCR_PKCS11_MODULE is
+// set, sign through the HSM so the key material never leaves the device;
+// otherwise fall back to the PEM file at path via PrivateKeySignerVerifier.
+func DefaultSignerVerifier(path string) IOE.IOEither[error, SignerVerifier] {
+	if cfg, ok := HasPKCS11Config(); ok {
+		return IOE.Of[error](SignerVerifier(pkcs11SignerVerifier{cfg: cfg}))
+	}
+	return PrivateKeySignerVerifier(path)
+}
@@ -0,0 +1,47 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ioeither
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	E "github.com/IBM/fp-go/either"
+	"github.com/ibm-hyper-protect/contract-go/crypto/pkcs11"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultSignerVerifierFallsBackToPrivateKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "key.priv")
+	assert.NoError(t, os.WriteFile(path, writeTestPrivateKey(t), 0600))
+
+	svE := DefaultSignerVerifier(path)()
+	assert.True(t, E.IsRight(svE))
+}
+
+func TestDefaultSignerVerifierPrefersPKCS11WhenConfigured(t *testing.T) {
+	t.Setenv(pkcs11.KeyEnvModule, "/usr/lib/softhsm/libsofthsm2.so")
+	t.Setenv(pkcs11.KeyEnvKeyLabel, "hpcr-signing-key")
+
+	svE := DefaultSignerVerifier("/does/not/matter")()
+	assert.True(t, E.IsRight(svE))
+
+	sv := E.Fold(
+		func(error) SignerVerifier { return nil },
+		func(s SignerVerifier) SignerVerifier { return s },
+	)(svE)
+	assert.Equal(t, "hpcr-signing-key", sv.KeyID())
+}
@@ -0,0 +1,39 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ioeither
+
+import (
+	F "github.com/IBM/fp-go/function"
+	IOE "github.com/IBM/fp-go/ioeither"
+)
+
+// ContractPayloadType is the DSSE payloadType contract-go uses to sign a
+// contract, mirroring how the legacy `hyper-protect-basic.<enc>.<enc>` token
+// format was always a contract, never a generic payload.
+const ContractPayloadType = "application/vnd.ibm.hpcr.contract+json"
+
+// SignContract is the production entry point for signing a contract: it
+// resolves the signing key via DefaultSignerVerifier (HSM first, PEM file -
+// possibly password encrypted - otherwise), then wraps the canonicalized
+// contract in a DSSE Envelope. This is what replaces building a
+// `hyper-protect-basic.<enc>.<enc>` token by hand.
+func SignContract(keyPath string, contract map[string]any) IOE.IOEither[error, Envelope] {
+	return F.Pipe1(
+		DefaultSignerVerifier(keyPath),
+		IOE.Chain(func(sv SignerVerifier) IOE.IOEither[error, Envelope] {
+			return SignDSSEContract(sv.Sign, sv.KeyID(), ContractPayloadType, contract)
+		}),
+	)
+}
@@ -0,0 +1,45 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ioeither
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	E "github.com/IBM/fp-go/either"
+	F "github.com/IBM/fp-go/function"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignContract(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "key.priv")
+	assert.NoError(t, os.WriteFile(path, writeTestPrivateKey(t), 0600))
+
+	contract := map[string]any{"workload": "x", "env": "y"}
+
+	envE := SignContract(path, contract)()
+	assert.True(t, E.IsRight(envE))
+
+	env := F.Pipe1(
+		envE,
+		E.Fold(
+			func(error) Envelope { return Envelope{} },
+			F.Identity[Envelope],
+		),
+	)
+	assert.Equal(t, ContractPayloadType, env.PayloadType)
+	assert.Len(t, env.Signatures, 1)
+}
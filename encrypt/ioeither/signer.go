@@ -0,0 +1,221 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ioeither
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+
+	E "github.com/IBM/fp-go/either"
+	IOE "github.com/IBM/fp-go/ioeither"
+)
+
+// algorithm names reported by SignerVerifier.Algorithm, also used to pick
+// the matching OpenSSL `-sigopt`/`-pkeyopt` flags.
+const (
+	AlgorithmRSAPSS    = "rsa-pss"
+	AlgorithmECDSAP256 = "ecdsa-p256"
+	AlgorithmECDSAP384 = "ecdsa-p384"
+	AlgorithmEd25519   = "ed25519"
+)
+
+// SignerVerifier abstracts over the concrete asymmetric algorithm used to
+// sign and verify a contract, so that callers such as SignDSSE/VerifyDSSE do
+// not need to know whether a key is RSA, ECDSA or Ed25519.
+type SignerVerifier interface {
+	// Sign produces a signature over digest.
+	Sign(digest []byte) IOE.IOEither[error, []byte]
+	// Verify checks sig against digest.
+	Verify(digest []byte, sig []byte) IOE.IOEither[error, bool]
+	// KeyID identifies the key. Implementations built from a parsed key derive
+	// it the same way as CryptoPrivKeyFingerprint; the PKCS#11 one returns the
+	// configured key label or key ID.
+	KeyID() string
+	// Algorithm is one of the Algorithm* constants.
+	Algorithm() string
+}
+
+type rsaSignerVerifier struct {
+	pub   *rsa.PublicKey
+	priv  *rsa.PrivateKey
+	keyID string
+}
+
+func (s *rsaSignerVerifier) Sign(digest []byte) IOE.IOEither[error, []byte] {
+	return IOE.TryCatchError(func() ([]byte, error) {
+		return rsa.SignPSS(rand.Reader, s.priv, crypto.SHA256, digest, nil)
+	})
+}
+
+func (s *rsaSignerVerifier) Verify(digest []byte, sig []byte) IOE.IOEither[error, bool] {
+	return IOE.TryCatchError(func() (bool, error) {
+		err := rsa.VerifyPSS(s.pub, crypto.SHA256, digest, sig, nil)
+		return err == nil, err
+	})
+}
+
+func (s *rsaSignerVerifier) KeyID() string     { return s.keyID }
+func (s *rsaSignerVerifier) Algorithm() string { return AlgorithmRSAPSS }
+
+type ecdsaSignerVerifier struct {
+	pub   *ecdsa.PublicKey
+	priv  *ecdsa.PrivateKey
+	keyID string
+	curve string
+}
+
+func (s *ecdsaSignerVerifier) Sign(digest []byte) IOE.IOEither[error, []byte] {
+	return IOE.TryCatchError(func() ([]byte, error) {
+		return ecdsa.SignASN1(rand.Reader, s.priv, digest)
+	})
+}
+
+func (s *ecdsaSignerVerifier) Verify(digest []byte, sig []byte) IOE.IOEither[error, bool] {
+	return IOE.TryCatchError(func() (bool, error) {
+		return ecdsa.VerifyASN1(s.pub, digest, sig), nil
+	})
+}
+
+func (s *ecdsaSignerVerifier) KeyID() string     { return s.keyID }
+func (s *ecdsaSignerVerifier) Algorithm() string { return s.curve }
+
+type ed25519SignerVerifier struct {
+	pub   ed25519.PublicKey
+	priv  ed25519.PrivateKey
+	keyID string
+}
+
+func (s *ed25519SignerVerifier) Sign(digest []byte) IOE.IOEither[error, []byte] {
+	return IOE.TryCatchError(func() ([]byte, error) {
+		return ed25519.Sign(s.priv, digest), nil
+	})
+}
+
+func (s *ed25519SignerVerifier) Verify(digest []byte, sig []byte) IOE.IOEither[error, bool] {
+	return IOE.TryCatchError(func() (bool, error) {
+		return ed25519.Verify(s.pub, digest, sig), nil
+	})
+}
+
+func (s *ed25519SignerVerifier) KeyID() string     { return s.keyID }
+func (s *ed25519SignerVerifier) Algorithm() string { return AlgorithmEd25519 }
+
+// pubOrCertToSignerVerifier generalizes pubOrCertToRsaKey to every algorithm
+// supported by SignerVerifier, dispatching on the concrete type of the
+// parsed crypto.PublicKey.
+func pubOrCertToSignerVerifier(data []byte) E.Either[error, SignerVerifier] {
+	return E.Eitherize0(func() (SignerVerifier, error) {
+		pub, err := parsePubOrCertKey(data)
+		if err != nil {
+			return nil, err
+		}
+		return signerVerifierFromPublicKey(pub)
+	})()
+}
+
+// parsePubOrCertKey accepts either a PEM encoded public key or certificate
+// and returns the embedded crypto.PublicKey, the same inputs pubOrCertToRsaKey
+// already handles for RSA.
+func parsePubOrCertKey(data []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("unable to decode PEM block")
+	}
+	switch block.Type {
+	case "CERTIFICATE":
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		return cert.PublicKey, nil
+	default:
+		return x509.ParsePKIXPublicKey(block.Bytes)
+	}
+}
+
+func signerVerifierFromPublicKey(pub crypto.PublicKey) (SignerVerifier, error) {
+	switch key := pub.(type) {
+	case *rsa.PublicKey:
+		return &rsaSignerVerifier{pub: key, keyID: keyIDFromPublicKey(pub)}, nil
+	case *ecdsa.PublicKey:
+		curve := AlgorithmECDSAP256
+		if key.Curve.Params().BitSize > 256 {
+			curve = AlgorithmECDSAP384
+		}
+		return &ecdsaSignerVerifier{pub: key, keyID: keyIDFromPublicKey(pub), curve: curve}, nil
+	case ed25519.PublicKey:
+		return &ed25519SignerVerifier{pub: key, keyID: keyIDFromPublicKey(pub)}, nil
+	default:
+		return nil, fmt.Errorf("unsupported public key type %T", pub)
+	}
+}
+
+// privKeyToSignerVerifier parses a PEM encoded PKCS#8 private key (the
+// format OpenSSLPrivateKey/CryptoPrivateKey already produce for RSA) and
+// returns a SignerVerifier able to both Sign and Verify, dispatching on the
+// concrete key type the same way pubOrCertToSignerVerifier does for public
+// keys. This is the production entry point: pubOrCertToSignerVerifier alone
+// only ever yields a verifier, since a public key or certificate never
+// carries the private half needed to sign.
+func privKeyToSignerVerifier(data []byte) E.Either[error, SignerVerifier] {
+	return E.Eitherize0(func() (SignerVerifier, error) {
+		block, _ := pem.Decode(data)
+		if block == nil {
+			return nil, fmt.Errorf("unable to decode PEM block")
+		}
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		return signerVerifierFromPrivateKey(key)
+	})()
+}
+
+func signerVerifierFromPrivateKey(key any) (SignerVerifier, error) {
+	switch priv := key.(type) {
+	case *rsa.PrivateKey:
+		return &rsaSignerVerifier{pub: &priv.PublicKey, priv: priv, keyID: keyIDFromPublicKey(&priv.PublicKey)}, nil
+	case *ecdsa.PrivateKey:
+		curve := AlgorithmECDSAP256
+		if priv.Curve.Params().BitSize > 256 {
+			curve = AlgorithmECDSAP384
+		}
+		return &ecdsaSignerVerifier{pub: &priv.PublicKey, priv: priv, keyID: keyIDFromPublicKey(&priv.PublicKey), curve: curve}, nil
+	case ed25519.PrivateKey:
+		pub := priv.Public().(ed25519.PublicKey)
+		return &ed25519SignerVerifier{pub: pub, priv: priv, keyID: keyIDFromPublicKey(pub)}, nil
+	default:
+		return nil, fmt.Errorf("unsupported private key type %T", key)
+	}
+}
+
+// keyIDFromPublicKey derives a stable key ID from the SHA-256 digest of the
+// public key's DER encoding, the same shape as CryptoPrivKeyFingerprint.
+func keyIDFromPublicKey(pub crypto.PublicKey) string {
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(der)
+	return hex.EncodeToString(sum[:])
+}
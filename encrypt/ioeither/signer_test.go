@@ -0,0 +1,114 @@
+// Copyright 2023 IBM Corp.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ioeither
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+
+	E "github.com/IBM/fp-go/either"
+	F "github.com/IBM/fp-go/function"
+	"github.com/stretchr/testify/assert"
+)
+
+func marshalPubPEM(t *testing.T, pub any) []byte {
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	assert.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+}
+
+func marshalPrivPEM(t *testing.T, priv any) []byte {
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	assert.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+}
+
+// signerVerifierFromPEM goes through the real production entry point rather
+// than poking unexported struct fields, so this test would catch the same
+// nil-priv panic a caller would have hit.
+func signerVerifierFromPEM(t *testing.T, priv any) SignerVerifier {
+	svE := privKeyToSignerVerifier(marshalPrivPEM(t, priv))
+	assert.True(t, E.IsRight(svE))
+	return F.Pipe1(
+		svE,
+		E.Fold(
+			func(error) SignerVerifier { return nil },
+			F.Identity[SignerVerifier],
+		),
+	)
+}
+
+// signerVerifierRoundTrip checks that a SignerVerifier produced for a given
+// key pair signs and verifies a digest correctly.
+func signerVerifierRoundTrip(t *testing.T, sv SignerVerifier, wantAlgorithm string) {
+	digest := sha256.Sum256([]byte("contract payload"))
+
+	sigE := sv.Sign(digest[:])()
+	assert.True(t, E.IsRight(sigE))
+
+	sig := E.Fold(
+		func(error) []byte { return nil },
+		func(s []byte) []byte { return s },
+	)(sigE)
+
+	okE := sv.Verify(digest[:], sig)()
+	ok := E.Fold(
+		func(error) bool { return false },
+		func(v bool) bool { return v },
+	)(okE)
+	assert.True(t, ok)
+
+	assert.Equal(t, wantAlgorithm, sv.Algorithm())
+	assert.NotEmpty(t, sv.KeyID())
+}
+
+func TestPubOrCertToSignerVerifierRSA(t *testing.T) {
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	svE := pubOrCertToSignerVerifier(marshalPubPEM(t, &priv.PublicKey))
+	assert.True(t, E.IsRight(svE))
+}
+
+func TestPrivKeyToSignerVerifierRSA(t *testing.T) {
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	sv := signerVerifierFromPEM(t, priv)
+	signerVerifierRoundTrip(t, sv, AlgorithmRSAPSS)
+}
+
+func TestPrivKeyToSignerVerifierECDSA(t *testing.T) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	sv := signerVerifierFromPEM(t, priv)
+	signerVerifierRoundTrip(t, sv, AlgorithmECDSAP256)
+}
+
+func TestPrivKeyToSignerVerifierEd25519(t *testing.T) {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+
+	sv := signerVerifierFromPEM(t, priv)
+	signerVerifierRoundTrip(t, sv, AlgorithmEd25519)
+}